@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DiffStatus describes how a rendered file compares to what's already on
+// disk under the output path (or a user-supplied --against directory).
+type DiffStatus string
+
+const (
+	DiffAdded     DiffStatus = "added"
+	DiffModified  DiffStatus = "modified"
+	DiffUnchanged DiffStatus = "unchanged"
+)
+
+// FileDiff reports the outcome of comparing one rendered file against the
+// existing contents of the output tree.
+type FileDiff struct {
+	Path   string
+	Status DiffStatus
+	Diff   string // unified diff body, empty when Status is DiffUnchanged
+}
+
+// RepoDiff summarizes the drift detected for one repository.
+type RepoDiff struct {
+	Repo  string
+	Files []FileDiff
+}
+
+// renderFileContent resolves a FileStructure's final templated content
+// without writing it anywhere, so it can be diffed or previewed. It mirrors
+// the content/sourceUrl/sourceFile precedence used by createFileFromTemplate.
+func renderFileContent(file FileStructure, sources map[string]string, partialsDir string) (string, error) {
+	name := filepath.Base(file.Filename)
+
+	switch {
+	case file.Content != "":
+		return renderTemplatedContent(name, file.Content, file.Values, partialsDir, file.Partials, sources)
+	case file.SourceURL != "":
+		content, err := downloadFileContent(file.SourceURL)
+		if err != nil {
+			return "", fmt.Errorf("downloading file from URL: %w", err)
+		}
+		if rendered, err := renderTemplatedContent(name, content, file.Values, partialsDir, file.Partials, sources); err == nil {
+			return rendered, nil
+		}
+		return content, nil
+	case file.SourceFile != "":
+		sourcePath, err := resolveSourceFile(file.SourceFile, sources)
+		if err != nil {
+			return "", fmt.Errorf("resolving source file: %w", err)
+		}
+		content, err := os.ReadFile(sourcePath)
+		if err != nil {
+			return "", fmt.Errorf("reading source file: %w", err)
+		}
+		if rendered, err := renderTemplatedContent(name, string(content), file.Values, partialsDir, file.Partials, sources); err == nil {
+			return rendered, nil
+		}
+		return string(content), nil
+	}
+	return "", nil
+}
+
+// collectRenderedFiles renders every file and group file belonging to repo,
+// keyed by their cleaned output-relative path, accumulating any per-file
+// failures into a MultiError instead of aborting on the first one.
+func collectRenderedFiles(repo Repository, globalGroups map[string][]FileStructure, sources map[string]string, partialsDir string) (map[string]string, error) {
+	rendered := make(map[string]string)
+	merr := &MultiError{}
+
+	addFile := func(group string, file FileStructure) {
+		expandedFiles, err := expandFileStructure(file, sources)
+		if err != nil {
+			merr.Add(repo.Name, group, file.Filename, fmt.Errorf("expanding file structure: %w", err))
+			return
+		}
+		for _, expandedFile := range expandedFiles {
+			content, err := renderFileContent(expandedFile, sources, partialsDir)
+			if err != nil {
+				merr.Add(repo.Name, group, expandedFile.Filename, err)
+				continue
+			}
+			rendered[filepath.Clean(expandedFile.Filename)] = content
+		}
+	}
+
+	for _, file := range repo.Files {
+		addFile("", file)
+	}
+
+	for _, groupRef := range repo.Groups {
+		group, exists := globalGroups[groupRef.GroupName]
+		if !exists {
+			merr.Add(repo.Name, groupRef.GroupName, "", fmt.Errorf("template group not found in configuration"))
+			continue
+		}
+		for _, file := range group {
+			file.Values = mergeValues(groupRef.Values, file.Values)
+			addFile(groupRef.GroupName, file)
+		}
+	}
+
+	return rendered, merr.ErrorOrNil()
+}
+
+// diffRepository renders every file for repo in memory and compares it to
+// the contents of compareRoot (the normal output path, or the --against
+// directory when given) without writing anything to disk.
+func diffRepository(repo Repository, globalGroups map[string][]FileStructure, sources map[string]string, compareRoot, partialsDir string) (RepoDiff, error) {
+	result := RepoDiff{Repo: repo.Name}
+	repoRoot := filepath.Join(compareRoot, repo.Name)
+
+	rendered, err := collectRenderedFiles(repo, globalGroups, sources, partialsDir)
+	if err != nil {
+		return result, err
+	}
+
+	for relPath, content := range rendered {
+		existingPath := filepath.Join(repoRoot, relPath)
+		existing, err := os.ReadFile(existingPath)
+
+		switch {
+		case os.IsNotExist(err):
+			result.Files = append(result.Files, FileDiff{Path: relPath, Status: DiffAdded, Diff: unifiedDiff(relPath, "", content)})
+		case err != nil:
+			return result, fmt.Errorf("reading existing file %s: %w", existingPath, err)
+		case string(existing) == content:
+			result.Files = append(result.Files, FileDiff{Path: relPath, Status: DiffUnchanged})
+		default:
+			result.Files = append(result.Files, FileDiff{Path: relPath, Status: DiffModified, Diff: unifiedDiff(relPath, string(existing), content)})
+		}
+	}
+
+	return result, nil
+}
+
+// runDiffMode renders every selected repository in memory and reports drift
+// against the existing output tree (or --against directory) without writing
+// anything, returning the process exit code: 0 when nothing has drifted, 1
+// otherwise so it can be wired into CI as a "templates out of sync" check.
+func runDiffMode(config Config, sources map[string]string, args CLIArgs) int {
+	compareRoot := args.OutputPath
+	if args.Against != "" {
+		compareRoot = args.Against
+	}
+
+	var added, modified, unchanged int
+	overall := &MultiError{}
+
+	for _, repoConfig := range config.Repositories {
+		if args.Repo != "" && repoConfig.Name != args.Repo {
+			continue
+		}
+		repo := Repository(repoConfig)
+
+		repoDiff, err := diffRepository(repo, config.TemplateGroups, sources, compareRoot, args.PartialsDir)
+		if err != nil {
+			if repoErrs, ok := err.(*MultiError); ok {
+				overall.Merge(repoErrs)
+			} else {
+				overall.Add(repo.Name, "", "", err)
+			}
+			continue
+		}
+
+		for _, file := range repoDiff.Files {
+			switch file.Status {
+			case DiffUnchanged:
+				unchanged++
+				continue
+			case DiffAdded:
+				added++
+			case DiffModified:
+				modified++
+			}
+			if args.Diff {
+				fmt.Println(file.Diff)
+			} else {
+				fmt.Printf("%s %s/%s\n", file.Status, repo.Name, file.Path)
+			}
+		}
+	}
+
+	fmt.Printf("\nSummary: %d added, %d modified, %d unchanged\n", added, modified, unchanged)
+
+	if overall.HasErrors() {
+		fmt.Println(overall.Error())
+		return 1
+	}
+	if added > 0 || modified > 0 {
+		return 1
+	}
+	return 0
+}
+
+// unifiedDiff renders a minimal unified diff between before and after,
+// labelled with path.
+func unifiedDiff(path, before, after string) string {
+	ops := lineDiff(splitLines(before), splitLines(after))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n", path)
+	fmt.Fprintf(&b, "+++ b/%s\n", path)
+	for _, op := range ops {
+		switch op.kind {
+		case diffOpEqual:
+			b.WriteString("  " + op.line + "\n")
+		case diffOpRemove:
+			b.WriteString("- " + op.line + "\n")
+		case diffOpAdd:
+			b.WriteString("+ " + op.line + "\n")
+		}
+	}
+	return b.String()
+}
+
+type diffOpKind int
+
+const (
+	diffOpEqual diffOpKind = iota
+	diffOpRemove
+	diffOpAdd
+)
+
+type diffOp struct {
+	kind diffOpKind
+	line string
+}
+
+// splitLines splits s on newlines, dropping a single trailing newline so an
+// otherwise-identical file doesn't report a spurious trailing blank line.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// lineDiff computes a minimal line-level edit script between a and b using
+// the standard LCS-based diff algorithm.
+func lineDiff(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{diffOpEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffOpRemove, a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffOpAdd, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{diffOpRemove, a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{diffOpAdd, b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,117 @@
+package main
+
+import "testing"
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want []diffOp
+	}{
+		{
+			name: "identical",
+			a:    []string{"one", "two"},
+			b:    []string{"one", "two"},
+			want: []diffOp{{diffOpEqual, "one"}, {diffOpEqual, "two"}},
+		},
+		{
+			name: "append",
+			a:    []string{"one"},
+			b:    []string{"one", "two"},
+			want: []diffOp{{diffOpEqual, "one"}, {diffOpAdd, "two"}},
+		},
+		{
+			name: "remove",
+			a:    []string{"one", "two"},
+			b:    []string{"one"},
+			want: []diffOp{{diffOpEqual, "one"}, {diffOpRemove, "two"}},
+		},
+		{
+			name: "replace middle",
+			a:    []string{"one", "two", "three"},
+			b:    []string{"one", "TWO", "three"},
+			want: []diffOp{
+				{diffOpEqual, "one"},
+				{diffOpRemove, "two"},
+				{diffOpAdd, "TWO"},
+				{diffOpEqual, "three"},
+			},
+		},
+		{
+			name: "empty before",
+			a:    nil,
+			b:    []string{"one"},
+			want: []diffOp{{diffOpAdd, "one"}},
+		},
+		{
+			name: "empty after",
+			a:    []string{"one"},
+			b:    nil,
+			want: []diffOp{{diffOpRemove, "one"}},
+		},
+		{
+			name: "both empty",
+			a:    nil,
+			b:    nil,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lineDiff(tt.a, tt.b)
+			if len(got) != len(tt.want) {
+				t.Fatalf("lineDiff(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("op[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "single line no newline", input: "foo", want: []string{"foo"}},
+		{name: "trailing newline dropped", input: "foo\nbar\n", want: []string{"foo", "bar"}},
+		{name: "no trailing newline", input: "foo\nbar", want: []string{"foo", "bar"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitLines(tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("splitLines(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("line[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffUnchangedHasNoMarkers(t *testing.T) {
+	diff := unifiedDiff("file.txt", "same\n", "same\n")
+	want := "--- a/file.txt\n+++ b/file.txt\n  same\n"
+	if diff != want {
+		t.Errorf("unifiedDiff = %q, want %q", diff, want)
+	}
+}
+
+func TestUnifiedDiffMarksChanges(t *testing.T) {
+	diff := unifiedDiff("file.txt", "old\n", "new\n")
+	want := "--- a/file.txt\n+++ b/file.txt\n- old\n+ new\n"
+	if diff != want {
+		t.Errorf("unifiedDiff = %q, want %q", diff, want)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileError records a single failure encountered while processing a repo,
+// group, or file, tagged with enough context to locate it in a bulk run
+// across many repositories.
+type FileError struct {
+	Repo  string
+	Group string
+	File  string
+	Err   error
+}
+
+// Error renders a FileError as "repo=... group=... file=...: <cause>",
+// omitting any segment that wasn't set.
+func (e FileError) Error() string {
+	var parts []string
+	if e.Repo != "" {
+		parts = append(parts, fmt.Sprintf("repo=%s", e.Repo))
+	}
+	if e.Group != "" {
+		parts = append(parts, fmt.Sprintf("group=%s", e.Group))
+	}
+	if e.File != "" {
+		parts = append(parts, fmt.Sprintf("file=%s", e.File))
+	}
+	return fmt.Sprintf("%s: %v", strings.Join(parts, " "), e.Err)
+}
+
+// Unwrap exposes the underlying cause for errors.Is/errors.As.
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError accumulates failures from independent units of work (files,
+// groups, repos) so that one bad template doesn't stop the rest of a fleet
+// run from being attempted.
+type MultiError struct {
+	Errors []FileError
+}
+
+// Add appends a failure to the MultiError.
+func (m *MultiError) Add(repo, group, file string, err error) {
+	m.Errors = append(m.Errors, FileError{Repo: repo, Group: group, File: file, Err: err})
+}
+
+// Merge appends every error from other, if any, onto m.
+func (m *MultiError) Merge(other *MultiError) {
+	if other == nil {
+		return
+	}
+	m.Errors = append(m.Errors, other.Errors...)
+}
+
+// HasErrors reports whether any failure has been recorded.
+func (m *MultiError) HasErrors() bool {
+	return m != nil && len(m.Errors) > 0
+}
+
+// ErrorOrNil returns m if it holds any errors, or nil otherwise, so a
+// function can keep returning a plain `error` even when it accumulates
+// failures internally rather than aborting on the first one.
+func (m *MultiError) ErrorOrNil() error {
+	if !m.HasErrors() {
+		return nil
+	}
+	return m
+}
+
+// Error renders a structured repo -> group -> file -> error report.
+func (m *MultiError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d error(s) occurred:", len(m.Errors))
+	for _, e := range m.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(e.Error())
+	}
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// globVars are the variables made available to a Filename template when a
+// FileStructure is expanded from a glob pattern.
+type globVars struct {
+	SourceBase    string // base name of the matched source file, e.g. "deploy.yml"
+	SourceRelPath string // path of the matched file relative to the glob root, extension included, e.g. "nested/deploy.yml"
+}
+
+// isGlobPattern reports whether pattern contains glob metacharacters.
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// expandFileStructure expands a FileStructure whose SourceFile or
+// SourcePattern contains a glob (including `**` for recursive matches) into
+// one FileStructure per matched file, each with its own resolved SourceFile
+// and a Filename rendered from the `{{.SourceBase}}`/`{{.SourceRelPath}}`
+// template. FileStructures without a glob pattern are returned unchanged.
+func expandFileStructure(file FileStructure, sources map[string]string) ([]FileStructure, error) {
+	pattern := file.SourcePattern
+	if pattern == "" {
+		pattern = file.SourceFile
+	}
+	if pattern == "" || !isGlobPattern(pattern) {
+		return []FileStructure{file}, nil
+	}
+
+	resolvedPattern, err := resolveSourceFile(pattern, sources)
+	if err != nil {
+		return nil, fmt.Errorf("resolving source pattern: %w", err)
+	}
+
+	root, matches, err := globMatches(resolvedPattern)
+	if err != nil {
+		return nil, fmt.Errorf("expanding glob %q: %w", pattern, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("glob pattern %q matched no files", pattern)
+	}
+
+	expanded := make([]FileStructure, 0, len(matches))
+	for _, match := range matches {
+		relPath, err := filepath.Rel(root, match)
+		if err != nil {
+			return nil, fmt.Errorf("computing relative path for %q: %w", match, err)
+		}
+
+		filename, err := renderFilenameTemplate(file.Filename, globVars{
+			SourceBase:    filepath.Base(match),
+			SourceRelPath: relPath,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("rendering filename template: %w", err)
+		}
+
+		expandedFile := file
+		expandedFile.SourcePattern = ""
+		expandedFile.SourceFile = match
+		expandedFile.Filename = filename
+		expanded = append(expanded, expandedFile)
+	}
+	return expanded, nil
+}
+
+// renderFilenameTemplate renders the Filename field as a text/template using
+// the glob match variables, so configs can derive a tree of output paths
+// from a single entry.
+func renderFilenameTemplate(filenamePattern string, vars globVars) (string, error) {
+	tmpl, err := template.New("filename").Parse(filenamePattern)
+	if err != nil {
+		return "", fmt.Errorf("parsing filename template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("executing filename template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// globMatches resolves pattern to its static root directory and the list of
+// files it matches, supporting a `**` segment for recursive matching in
+// addition to the stdlib filepath.Glob semantics.
+func globMatches(pattern string) (root string, matches []string, err error) {
+	if !strings.Contains(pattern, "**") {
+		root = staticGlobRoot(pattern)
+		matches, err = filepath.Glob(pattern)
+		sort.Strings(matches)
+		return root, matches, err
+	}
+
+	prefix, suffix, _ := strings.Cut(pattern, "**")
+	root = strings.TrimRight(prefix, string(filepath.Separator))
+	suffix = strings.TrimPrefix(suffix, string(filepath.Separator))
+
+	matches, err = walkDoubleStar(root, suffix)
+	sort.Strings(matches)
+	return root, matches, err
+}
+
+// walkDoubleStar walks root recursively, matching suffix (a glob pattern
+// without `**`) against each file's path relative to root.
+func walkDoubleStar(root, suffix string) ([]string, error) {
+	var matches []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		ok, err := filepath.Match(suffix, rel)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// Also allow the suffix to match just the base name, so
+			// `dir/**/*.tmpl` reaches files nested arbitrarily deep.
+			ok, err = filepath.Match(suffix, filepath.Base(rel))
+			if err != nil {
+				return err
+			}
+		}
+		if ok {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// staticGlobRoot returns the deepest directory in pattern that contains no
+// glob metacharacters, used as the base for computing SourceRelPath.
+func staticGlobRoot(pattern string) string {
+	dir := filepath.Dir(pattern)
+	for isGlobPattern(dir) {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
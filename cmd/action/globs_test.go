@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// writeTestFiles creates each relative path under dir with empty content,
+// including any parent directories.
+func writeTestFiles(t *testing.T, dir string, relPaths ...string) {
+	t.Helper()
+	for _, rel := range relPaths {
+		full := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(full), os.ModePerm); err != nil {
+			t.Fatalf("mkdir %s: %v", full, err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", full, err)
+		}
+	}
+}
+
+func TestGlobMatchesSingleStar(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, "ci/one.yml", "ci/two.yml", "ci/readme.md")
+
+	root, matches, err := globMatches(filepath.Join(dir, "ci", "*.yml"))
+	if err != nil {
+		t.Fatalf("globMatches returned error: %v", err)
+	}
+	if root != filepath.Join(dir, "ci") {
+		t.Errorf("root = %q, want %q", root, filepath.Join(dir, "ci"))
+	}
+	if len(matches) != 2 {
+		t.Fatalf("matches = %v, want 2 entries", matches)
+	}
+}
+
+func TestGlobMatchesDoubleStar(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir,
+		"manifests/a.tmpl",
+		"manifests/nested/b.tmpl",
+		"manifests/nested/deep/c.tmpl",
+		"manifests/skip.txt",
+	)
+
+	root, matches, err := globMatches(filepath.Join(dir, "manifests", "**", "*.tmpl"))
+	if err != nil {
+		t.Fatalf("globMatches returned error: %v", err)
+	}
+	if root != filepath.Join(dir, "manifests") {
+		t.Errorf("root = %q, want %q", root, filepath.Join(dir, "manifests"))
+	}
+	sort.Strings(matches)
+	want := []string{
+		filepath.Join(dir, "manifests", "a.tmpl"),
+		filepath.Join(dir, "manifests", "nested", "b.tmpl"),
+		filepath.Join(dir, "manifests", "nested", "deep", "c.tmpl"),
+	}
+	sort.Strings(want)
+	if len(matches) != len(want) {
+		t.Fatalf("matches = %v, want %v", matches, want)
+	}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Errorf("matches[%d] = %q, want %q", i, matches[i], want[i])
+		}
+	}
+}
+
+func TestGlobMatchesNoResults(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, "ci/one.yml")
+
+	_, matches, err := globMatches(filepath.Join(dir, "ci", "*.nope"))
+	if err != nil {
+		t.Fatalf("globMatches returned error: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("matches = %v, want none", matches)
+	}
+}
+
+func TestExpandFileStructureRendersFilenameTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, "ci/build.yml", "ci/deploy.yml")
+
+	file := FileStructure{
+		SourcePattern: filepath.Join(dir, "ci", "*.yml"),
+		Filename:      "out/{{.SourceBase}}",
+	}
+
+	expanded, err := expandFileStructure(file, nil)
+	if err != nil {
+		t.Fatalf("expandFileStructure returned error: %v", err)
+	}
+	if len(expanded) != 2 {
+		t.Fatalf("expanded = %v, want 2 entries", expanded)
+	}
+
+	got := make(map[string]string)
+	for _, f := range expanded {
+		got[f.Filename] = f.SourceFile
+	}
+	for _, name := range []string{"out/build.yml", "out/deploy.yml"} {
+		if _, ok := got[name]; !ok {
+			t.Errorf("missing expanded filename %q in %v", name, got)
+		}
+	}
+}
+
+func TestExpandFileStructureNoMatchesErrors(t *testing.T) {
+	dir := t.TempDir()
+	file := FileStructure{
+		SourcePattern: filepath.Join(dir, "missing", "*.yml"),
+		Filename:      "out/{{.SourceBase}}",
+	}
+
+	if _, err := expandFileStructure(file, nil); err == nil {
+		t.Fatal("expected an error for a glob pattern matching no files")
+	}
+}
+
+func TestExpandFileStructurePassesThroughNonGlob(t *testing.T) {
+	file := FileStructure{SourceFile: "plain/path.yml", Filename: "out.yml"}
+
+	expanded, err := expandFileStructure(file, nil)
+	if err != nil {
+		t.Fatalf("expandFileStructure returned error: %v", err)
+	}
+	if len(expanded) != 1 || expanded[0].Filename != file.Filename || expanded[0].SourceFile != file.SourceFile {
+		t.Errorf("expandFileStructure should pass non-glob files through unchanged, got %v", expanded)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// HookConfig describes the post-generation pipeline for a repository: clone
+// a target repo, copy the freshly generated files over it, commit, and
+// optionally push and open a pull request. It can be set globally as a
+// default and overridden per repository.
+type HookConfig struct {
+	TargetRepo    string    `yaml:"targetRepo"`
+	Branch        string    `yaml:"branch"`
+	CommitMessage string    `yaml:"commitMessage"`
+	PR            *PRConfig `yaml:"pr"`
+}
+
+// PRConfig configures the pull request opened by the "pr" hook stage.
+type PRConfig struct {
+	Backend string `yaml:"backend"` // github|gitlab
+	Title   string `yaml:"title"`
+	Body    string `yaml:"body"`
+	Base    string `yaml:"base"`
+}
+
+// HookRunOptions carries the run-wide hook settings shared by every
+// repository: which stages were selected on the command line, the global
+// default hook config, and the workdir hook clones are checked out into.
+type HookRunOptions struct {
+	Global  *HookConfig
+	Workdir string
+	Stages  map[string]bool
+}
+
+// parseHookStages turns a `--hooks=commit,push,pr` flag value into a set of
+// selected stage names. An empty string selects no stages, so hooks are
+// opt-in even when a repo configures a targetRepo.
+func parseHookStages(s string) map[string]bool {
+	stages := make(map[string]bool)
+	for _, stage := range strings.Split(s, ",") {
+		stage = strings.TrimSpace(stage)
+		if stage != "" {
+			stages[stage] = true
+		}
+	}
+	return stages
+}
+
+// resolveHooks returns the repo's own hook config if set, falling back to
+// the run's global default otherwise.
+func resolveHooks(repoHooks, globalHooks *HookConfig) *HookConfig {
+	if repoHooks != nil {
+		return repoHooks
+	}
+	return globalHooks
+}
+
+// runHooks executes the configured post-generation pipeline for repo:
+// clone its hook target into a workdir, copy the freshly generated files
+// over the clone, then run the stages selected via --hooks in the fixed order
+// commit, push, pr. A stage runs only when it and every stage before it were
+// selected, so "push" without "commit" or "pr" without "push" does nothing
+// beyond the clone and copy. It is a no-op when hooks is nil, has no
+// targetRepo, or no stages were selected.
+func runHooks(repo Repository, hooks *HookConfig, generatedPath string, opts HookRunOptions) error {
+	if hooks == nil || hooks.TargetRepo == "" || len(opts.Stages) == 0 {
+		return nil
+	}
+
+	repoWorkdir := filepath.Join(opts.Workdir, repo.Name)
+	if err := cloneHookTarget(hooks, repoWorkdir); err != nil {
+		return fmt.Errorf("cloning hook target: %w", err)
+	}
+
+	if err := copyGeneratedFiles(generatedPath, repoWorkdir); err != nil {
+		return fmt.Errorf("copying generated files: %w", err)
+	}
+
+	if !opts.Stages["commit"] {
+		return nil
+	}
+	message, err := renderTemplateString("commitMessage", hooks.CommitMessage, map[string]interface{}{"Repo": repo.Name})
+	if err != nil {
+		return fmt.Errorf("rendering commit message: %w", err)
+	}
+	if err := runGit(repoWorkdir, "add", "-A"); err != nil {
+		return fmt.Errorf("git add: %w", err)
+	}
+	if err := runGit(repoWorkdir, "commit", "-m", message); err != nil {
+		return fmt.Errorf("git commit: %w", err)
+	}
+
+	if !opts.Stages["push"] {
+		return nil
+	}
+	branch := hooks.Branch
+	if branch == "" {
+		branch = "main"
+	}
+	if err := runGit(repoWorkdir, "push", "origin", "HEAD:"+branch); err != nil {
+		return fmt.Errorf("git push: %w", err)
+	}
+
+	if !opts.Stages["pr"] {
+		return nil
+	}
+	if hooks.PR == nil {
+		return fmt.Errorf("pr hook selected but no pr config set for repo %s", repo.Name)
+	}
+	backend, err := resolvePRBackend(hooks.PR.Backend)
+	if err != nil {
+		return fmt.Errorf("resolving PR backend: %w", err)
+	}
+	if err := backend.OpenPR(repo, hooks, branch); err != nil {
+		return fmt.Errorf("opening pull request: %w", err)
+	}
+	return nil
+}
+
+// cloneHookTarget clones hooks.TargetRepo into dir, replacing any existing
+// checkout, and checks out hooks.Branch when one is configured.
+func cloneHookTarget(hooks *HookConfig, dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("clearing workdir: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), os.ModePerm); err != nil {
+		return fmt.Errorf("creating workdir: %w", err)
+	}
+
+	args := []string{"clone"}
+	if hooks.Branch != "" {
+		args = append(args, "--branch", hooks.Branch)
+	}
+	args = append(args, hooks.TargetRepo, dir)
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// copyGeneratedFiles copies every file under src into dst, preserving the
+// directory structure, overwriting whatever the hook target clone already
+// has at those paths.
+func copyGeneratedFiles(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, os.ModePerm)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		return copyFile(path, target)
+	})
+}
+
+// runGit runs a git subcommand with dir as its working directory.
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// PRBackend opens a pull request against a hosted git provider once the
+// hook's commit has been pushed.
+type PRBackend interface {
+	OpenPR(repo Repository, hooks *HookConfig, branch string) error
+}
+
+// resolvePRBackend looks up a PRBackend by name, defaulting to "github"
+// when name is empty.
+func resolvePRBackend(name string) (PRBackend, error) {
+	switch name {
+	case "", "github":
+		return githubPRBackend{}, nil
+	case "gitlab":
+		return gitlabPRBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported PR backend: %s", name)
+	}
+}
+
+// githubPRBackend opens pull requests via the GitHub REST API, authenticated
+// with a token read from the GITHUB_TOKEN environment variable.
+type githubPRBackend struct{}
+
+func (githubPRBackend) OpenPR(repo Repository, hooks *HookConfig, branch string) error {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return fmt.Errorf("GITHUB_TOKEN is not set")
+	}
+
+	ownerRepo := strings.TrimSuffix(strings.TrimPrefix(hooks.TargetRepo, "https://github.com/"), ".git")
+
+	title, err := renderTemplateString("prTitle", hooks.PR.Title, map[string]interface{}{"Repo": repo.Name})
+	if err != nil {
+		return fmt.Errorf("rendering PR title: %w", err)
+	}
+	body, err := renderTemplateString("prBody", hooks.PR.Body, map[string]interface{}{"Repo": repo.Name})
+	if err != nil {
+		return fmt.Errorf("rendering PR body: %w", err)
+	}
+	base := hooks.PR.Base
+	if base == "" {
+		base = "main"
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"title": title,
+		"body":  body,
+		"head":  branch,
+		"base":  base,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding PR payload: %w", err)
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/pulls", ownerRepo)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("building PR request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("creating pull request: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			fmt.Printf("error closing response body: %v\n", cerr)
+		}
+	}()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("GitHub API returned %s", resp.Status)
+	}
+	return nil
+}
+
+// gitlabPRBackend is the "gitlab" backend. It checks that GITLAB_TOKEN is set
+// and then returns an error; it does not open merge requests.
+type gitlabPRBackend struct{}
+
+func (gitlabPRBackend) OpenPR(repo Repository, hooks *HookConfig, branch string) error {
+	if os.Getenv("GITLAB_TOKEN") == "" {
+		return fmt.Errorf("GITLAB_TOKEN is not set")
+	}
+	return fmt.Errorf("gitlab PR backend is not implemented yet")
+}
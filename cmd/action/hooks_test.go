@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHookStages(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]bool
+	}{
+		{name: "empty selects nothing", in: "", want: map[string]bool{}},
+		{name: "single stage", in: "commit", want: map[string]bool{"commit": true}},
+		{name: "multiple stages", in: "commit,push,pr", want: map[string]bool{"commit": true, "push": true, "pr": true}},
+		{name: "trims whitespace", in: " commit , push ", want: map[string]bool{"commit": true, "push": true}},
+		{name: "ignores blank entries", in: "commit,,push", want: map[string]bool{"commit": true, "push": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseHookStages(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHookStages(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveHooks(t *testing.T) {
+	repoHooks := &HookConfig{TargetRepo: "repo"}
+	globalHooks := &HookConfig{TargetRepo: "global"}
+
+	if got := resolveHooks(repoHooks, globalHooks); got != repoHooks {
+		t.Errorf("resolveHooks should prefer repo hooks when set, got %v", got)
+	}
+	if got := resolveHooks(nil, globalHooks); got != globalHooks {
+		t.Errorf("resolveHooks should fall back to global hooks, got %v", got)
+	}
+	if got := resolveHooks(nil, nil); got != nil {
+		t.Errorf("resolveHooks(nil, nil) = %v, want nil", got)
+	}
+}
+
+func TestRunHooksNoOpWithoutTarget(t *testing.T) {
+	repo := Repository{Name: "svc"}
+	opts := HookRunOptions{Stages: map[string]bool{"commit": true}}
+
+	if err := runHooks(repo, nil, t.TempDir(), opts); err != nil {
+		t.Errorf("runHooks with nil hooks should be a no-op, got error: %v", err)
+	}
+	if err := runHooks(repo, &HookConfig{}, t.TempDir(), opts); err != nil {
+		t.Errorf("runHooks with no TargetRepo should be a no-op, got error: %v", err)
+	}
+}
+
+func TestRunHooksNoOpWithoutSelectedStages(t *testing.T) {
+	repo := Repository{Name: "svc"}
+	hooks := &HookConfig{TargetRepo: "https://example.com/repo.git"}
+	opts := HookRunOptions{Stages: map[string]bool{}}
+
+	if err := runHooks(repo, hooks, t.TempDir(), opts); err != nil {
+		t.Errorf("runHooks with no selected stages should be a no-op, got error: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"text/template"
 	"time"
@@ -32,6 +34,16 @@ type CLIArgs struct {
 	TemplatesDir string `arg:"-t,--templates, help:Directory where template files are stored"`
 	Repo         string `arg:"-r,--repo, help:Specify a single repository to render"`
 	MaxParallel  int    `arg:"-p,--max-parallel, help:Maximum number of repositories to process in parallel"`
+	Offline      bool   `arg:"--offline, help:Do not fetch remote sources; use what is already cached"`
+	DryRun       bool   `arg:"--dry-run, help:Render files in memory and report drift without writing anything"`
+	Diff         bool   `arg:"--diff, help:Like --dry-run, but print a unified diff instead of a status line for every changed file"`
+	Against      string `arg:"--against, help:Compare rendered output against this directory instead of --output"`
+	Hooks        string `arg:"--hooks, help:Comma-separated post-generation hook stages to run (commit,push,pr)"`
+	Workdir      string `arg:"--workdir, help:Directory to check out hook target repositories into"`
+	Silent       bool   `arg:"--silent, help:Suppress all progress output"`
+	NoProgress   bool   `arg:"--no-progress, help:Disable the progress bar even when stderr is a terminal"`
+	LogFormat    string `arg:"--log-format, help:Set to 'json' to emit one JSON event per line instead of the progress bar"`
+	PartialsDir  string `arg:"--partials-dir, help:Directory of shared partial templates available to every render"`
 }
 
 // Version returns a formatted string with application version details.
@@ -41,8 +53,10 @@ func (CLIArgs) Version() string {
 
 // Config represents the structure of the configuration file.
 type Config struct {
+	Sources        []SourceConfig             `yaml:"sources"`
 	TemplateGroups map[string][]FileStructure `yaml:"templateGroups"`
 	Repositories   []RepositoryConfig         `yaml:"repositories"`
+	Hooks          *HookConfig                `yaml:"hooks"`
 }
 
 // RepositoryConfig defines the configuration of a single repository.
@@ -50,6 +64,7 @@ type RepositoryConfig struct {
 	Name   string             `yaml:"name"`
 	Files  []FileStructure    `yaml:"files"`
 	Groups []TemplateGroupRef `yaml:"groups"`
+	Hooks  *HookConfig        `yaml:"hooks"`
 }
 
 // TemplateGroupRef links a template group with specific values.
@@ -60,11 +75,13 @@ type TemplateGroupRef struct {
 
 // FileStructure describes a file to be created from a template or URL.
 type FileStructure struct {
-	Filename   string `yaml:"filename"`
-	SourceFile string `yaml:"sourceFile"`
-	SourceURL  string `yaml:"sourceUrl"`
-	Content    string `yaml:"content"`
-	Values     map[string]interface{}
+	Filename      string   `yaml:"filename"`
+	SourceFile    string   `yaml:"sourceFile"`
+	SourcePattern string   `yaml:"sourcePattern"`
+	SourceURL     string   `yaml:"sourceUrl"`
+	Content       string   `yaml:"content"`
+	Partials      []string `yaml:"partials"`
+	Values        map[string]interface{}
 }
 
 // Template represents a template consisting of multiple files.
@@ -80,6 +97,7 @@ type Repository struct {
 	Name   string
 	Files  []FileStructure
 	Groups []TemplateGroupRef
+	Hooks  *HookConfig
 }
 
 // main is the entry point of the application.
@@ -88,6 +106,8 @@ func main() {
 	args.OutputPath = "out"         // Default output path
 	args.TemplatesDir = "templates" // Default templates directory
 	args.MaxParallel = 5            // Default maximum parallel processing
+	args.Workdir = "workdir"        // Default hook checkout directory
+	args.PartialsDir = "partials"   // Default shared partials directory
 
 	arg.MustParse(&args)
 
@@ -107,16 +127,40 @@ func main() {
 		log.Fatalf("Configuration validation error: %v\n", err)
 	}
 
-	startTime := time.Now()
+	log.Println("Resolving template sources...")
+	sources, err := resolveSources(config.Sources, args.Offline)
+	if err != nil {
+		log.Fatalf("Error resolving sources: %v\n", err)
+	}
 
-	log.Printf("Processing repositories in parallel (max-parallel: %d) ...", args.MaxParallel)
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, args.MaxParallel) // Semaphore to limit parallelism
+	if args.DryRun || args.Diff {
+		os.Exit(runDiffMode(config, sources, args))
+	}
 
+	hookOpts := HookRunOptions{
+		Global:  config.Hooks,
+		Workdir: args.Workdir,
+		Stages:  parseHookStages(args.Hooks),
+	}
+
+	selectedRepos := make([]RepositoryConfig, 0, len(config.Repositories))
 	for _, repoConfig := range config.Repositories {
 		if args.Repo != "" && repoConfig.Name != args.Repo {
 			continue
 		}
+		selectedRepos = append(selectedRepos, repoConfig)
+	}
+
+	reporter := newReporter(len(selectedRepos), args.Silent, args.NoProgress, args.LogFormat, os.Stderr)
+
+	startTime := time.Now()
+
+	log.Printf("Processing repositories in parallel (max-parallel: %d) ...", args.MaxParallel)
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, args.MaxParallel) // Semaphore to limit parallelism
+	results := make(chan error, len(selectedRepos))
+
+	for _, repoConfig := range selectedRepos {
 		wg.Add(1)
 		semaphore <- struct{}{} // Acquire semaphore
 
@@ -124,18 +168,36 @@ func main() {
 			defer wg.Done()
 			defer func() { <-semaphore }() // Release semaphore
 
-			log.Printf("Processing repository: %s\n", rc.Name)
 			repo := Repository(rc)
-			if err := processRepository(repo, config.TemplateGroups, args.OutputPath); err != nil {
-				log.Printf("Error processing repository %s: %v\n", repo.Name, err)
-			}
+			results <- processRepository(repo, config.TemplateGroups, sources, args.OutputPath, args.PartialsDir, hookOpts, reporter)
 		}(repoConfig)
 	}
 
 	wg.Wait() // Wait for all goroutines to finish
+	close(results)
+
+	overall := &MultiError{}
+	for err := range results {
+		if err == nil {
+			continue
+		}
+		if repoErrs, ok := err.(*MultiError); ok {
+			overall.Merge(repoErrs)
+		} else {
+			overall.Add("", "", "", err)
+		}
+	}
+
+	reporter.Close()
 
 	duration := time.Since(startTime)
-	log.Printf("Parallel processing completed in %.2fs. Have a great day!\n", duration.Seconds())
+	log.Printf("Parallel processing completed in %.2fs.\n", duration.Seconds())
+
+	if overall.HasErrors() {
+		log.Printf("Completed with failures:\n%s\n", overall.Error())
+		os.Exit(1)
+	}
+	log.Println("Have a great day!")
 }
 
 // readConfig reads and parses the YAML configuration file.
@@ -153,33 +215,39 @@ func readConfig(filename, templatesDir string) (Config, error) {
 
 	for _, group := range config.TemplateGroups {
 		for i, file := range group {
-			if file.SourceFile != "" {
+			if file.SourceFile != "" && !strings.Contains(file.SourceFile, ":") {
 				group[i].SourceFile = filepath.Join(templatesDir, file.SourceFile)
 			}
+			if file.SourcePattern != "" && !strings.Contains(file.SourcePattern, ":") {
+				group[i].SourcePattern = filepath.Join(templatesDir, file.SourcePattern)
+			}
 		}
 	}
 	log.Println("Configuration read successfully.")
 	return config, nil
 }
 
-// validateConfig performs various checks on the configuration.
+// validateConfig performs various checks on the configuration, running every
+// check even if an earlier one fails so a failure in one check doesn't hide
+// problems found by the others. Each check reports only its first problem.
 func validateConfig(config Config) error {
+	merr := &MultiError{}
 	if err := validateDuplicateRepoNames(config.Repositories); err != nil {
-		return err
+		merr.Add("", "", "repositories", err)
 	}
 	if err := validateDuplicateTemplateGroups(config.TemplateGroups); err != nil {
-		return err
+		merr.Add("", "", "templateGroups", err)
 	}
 	if err := validateFileStructures(config.TemplateGroups); err != nil {
-		return err
+		merr.Add("", "", "templateGroups", err)
 	}
 	if err := validateRepoGroupReferences(config.Repositories, config.TemplateGroups); err != nil {
-		return err
+		merr.Add("", "", "repositories", err)
 	}
 	if err := validateURLSchemes(config.TemplateGroups); err != nil {
-		return err
+		merr.Add("", "", "templateGroups", err)
 	}
-	return nil
+	return merr.ErrorOrNil()
 }
 
 // validateDuplicateRepoNames checks for duplicate repository names.
@@ -213,11 +281,23 @@ func validateFileStructures(groups map[string][]FileStructure) error {
 			if file.SourceFile != "" && file.Content != "" {
 				return fmt.Errorf("both SourceFile and Content set for file: %s", file.Filename)
 			}
-			if file.SourceFile != "" {
-				if _, err := os.Stat(file.SourceFile); os.IsNotExist(err) {
+			if file.SourcePattern != "" && (file.SourceFile != "" || file.Content != "") {
+				return fmt.Errorf("SourcePattern cannot be combined with SourceFile or Content for file: %s", file.Filename)
+			}
+			if file.SourceFile != "" && !strings.Contains(file.SourceFile, ":") {
+				if isGlobPattern(file.SourceFile) {
+					if _, err := expandFileStructure(file, nil); err != nil {
+						return fmt.Errorf("invalid sourceFile glob for file %s: %w", file.Filename, err)
+					}
+				} else if _, err := os.Stat(file.SourceFile); os.IsNotExist(err) {
 					return fmt.Errorf("template file not found: %s", file.SourceFile)
 				}
 			}
+			if file.SourcePattern != "" && !strings.Contains(file.SourcePattern, ":") {
+				if _, err := expandFileStructure(file, nil); err != nil {
+					return fmt.Errorf("invalid sourcePattern glob for file %s: %w", file.Filename, err)
+				}
+			}
 		}
 	}
 	return nil
@@ -263,24 +343,41 @@ func deleteExistingDir(dirPath string) error {
 	return nil
 }
 
-// processRepository processes a single repository configuration.
-func processRepository(repo Repository, globalGroups map[string][]FileStructure, outputPath string) error {
+// processRepository processes a single repository configuration, collecting
+// failures from individual files and groups into a MultiError rather than
+// aborting the repo on the first one, so a single bad template doesn't take
+// down the rest of the repo's files.
+func processRepository(repo Repository, globalGroups map[string][]FileStructure, sources map[string]string, outputPath, partialsDir string, hookOpts HookRunOptions, reporter Reporter) error {
+	reporter.RepoStart(repo.Name)
+
 	repoOutputPath := filepath.Join(outputPath, repo.Name)
 
 	if err := deleteExistingDir(repoOutputPath); err != nil {
-		return fmt.Errorf("error clearing output directory for repo %s: %w", repo.Name, err)
+		err = fmt.Errorf("error clearing output directory for repo %s: %w", repo.Name, err)
+		reporter.RepoDone(repo.Name, err)
+		return err
 	}
 
+	merr := &MultiError{}
+
 	for _, file := range repo.Files {
-		if err := createFileFromTemplate(repo.Name, file, outputPath); err != nil {
-			return fmt.Errorf("error creating file from template: %w", err)
+		expandedFiles, err := expandFileStructure(file, sources)
+		if err != nil {
+			merr.Add(repo.Name, "", file.Filename, fmt.Errorf("expanding file structure: %w", err))
+			continue
+		}
+		for _, expandedFile := range expandedFiles {
+			if err := createFileFromTemplate(repo.Name, expandedFile, sources, outputPath, partialsDir, reporter); err != nil {
+				merr.Add(repo.Name, "", expandedFile.Filename, err)
+			}
 		}
 	}
 
 	for _, groupRef := range repo.Groups {
 		group, exists := globalGroups[groupRef.GroupName]
 		if !exists {
-			return fmt.Errorf("template group %s not found in configuration", groupRef.GroupName)
+			merr.Add(repo.Name, groupRef.GroupName, "", fmt.Errorf("template group not found in configuration"))
+			continue
 		}
 
 		template := Template{Files: make([]FileStructure, len(group))}
@@ -291,12 +388,28 @@ func processRepository(repo Repository, globalGroups map[string][]FileStructure,
 			template.Files[i].Values = mergedValues
 		}
 
-		if err := generateFilesFromTemplate(repo.Name, template, outputPath); err != nil {
-			return fmt.Errorf("error generating template for repo %s: %w", repo.Name, err)
+		if err := generateFilesFromTemplate(repo.Name, groupRef.GroupName, template, sources, outputPath, partialsDir, reporter); err != nil {
+			if groupErrs, ok := err.(*MultiError); ok {
+				merr.Merge(groupErrs)
+			} else {
+				merr.Add(repo.Name, groupRef.GroupName, "", err)
+			}
 		}
 	}
 
-	log.Printf("Repository '%s' processed successfully.\n", repo.Name)
+	if !merr.HasErrors() {
+		hooks := resolveHooks(repo.Hooks, hookOpts.Global)
+		if err := runHooks(repo, hooks, repoOutputPath, hookOpts); err != nil {
+			merr.Add(repo.Name, "", "hooks", err)
+		}
+	}
+
+	if merr.HasErrors() {
+		reporter.RepoDone(repo.Name, merr)
+		return merr
+	}
+
+	reporter.RepoDone(repo.Name, nil)
 	return nil
 }
 
@@ -314,18 +427,45 @@ func mergeValues(groupValues, fileValues map[string]interface{}) map[string]inte
 	return merged
 }
 
-// generateFilesFromTemplate generates files for a repository based on the provided template.
-func generateFilesFromTemplate(repoName string, t Template, outputPath string) error {
+// generateFilesFromTemplate generates files for a repository based on the
+// provided template, collecting a failure for each file that errors rather
+// than stopping at the first one so the rest of the group still renders.
+func generateFilesFromTemplate(repoName, groupName string, t Template, sources map[string]string, outputPath, partialsDir string, reporter Reporter) error {
+	merr := &MultiError{}
 	for _, file := range t.Files {
-		if err := createFileFromTemplate(repoName, file, outputPath); err != nil {
-			return fmt.Errorf("error creating file from template: %w", err)
+		expandedFiles, err := expandFileStructure(file, sources)
+		if err != nil {
+			merr.Add(repoName, groupName, file.Filename, fmt.Errorf("expanding file structure: %w", err))
+			continue
+		}
+		for _, expandedFile := range expandedFiles {
+			if err := createFileFromTemplate(repoName, expandedFile, sources, outputPath, partialsDir, reporter); err != nil {
+				merr.Add(repoName, groupName, expandedFile.Filename, err)
+			}
 		}
 	}
+	return merr.ErrorOrNil()
+}
+
+// createFileFromTemplate creates a file based on the FileStructure details,
+// reporting the outcome to reporter so large fleets stay observable.
+func createFileFromTemplate(repoName string, file FileStructure, sources map[string]string, outputPath, partialsDir string, reporter Reporter) error {
+	if file.Content == "" && file.SourceURL == "" && file.SourceFile == "" {
+		reporter.FileSkipped(repoName, file.Filename, "no content, sourceUrl, or sourceFile set")
+		return nil
+	}
+
+	if err := writeFileFromTemplate(repoName, file, sources, outputPath, partialsDir); err != nil {
+		reporter.Error(repoName, file.Filename, err)
+		return err
+	}
+	reporter.FileWritten(repoName, file.Filename)
 	return nil
 }
 
-// createFileFromTemplate creates a file based on the FileStructure details.
-func createFileFromTemplate(repoName string, file FileStructure, outputPath string) error {
+// writeFileFromTemplate resolves a file's content (from Content, SourceURL or
+// SourceFile) and writes it to disk under outputPath.
+func writeFileFromTemplate(repoName string, file FileStructure, sources map[string]string, outputPath, partialsDir string) error {
 	outputPath = filepath.Join(outputPath, repoName, filepath.Dir(file.Filename))
 	if err := os.MkdirAll(outputPath, os.ModePerm); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
@@ -335,22 +475,26 @@ func createFileFromTemplate(repoName string, file FileStructure, outputPath stri
 
 	// Create file based on content, URL or file source.
 	if file.Content != "" {
-		return createTemplatedFile(fullPath, file.Content, file.Values)
+		return createTemplatedFile(fullPath, file.Content, file.Values, partialsDir, file.Partials, sources)
 	} else if file.SourceURL != "" {
 		content, err := downloadFileContent(file.SourceURL)
 		if err != nil {
 			return fmt.Errorf("downloading file from URL: %w", err)
 		}
-		if err := createTemplatedFile(fullPath, content, file.Values); err != nil {
+		if err := createTemplatedFile(fullPath, content, file.Values, partialsDir, file.Partials, sources); err != nil {
 			return copyContentToFile(content, fullPath)
 		}
 	} else if file.SourceFile != "" {
-		content, err := os.ReadFile(file.SourceFile)
+		sourcePath, err := resolveSourceFile(file.SourceFile, sources)
+		if err != nil {
+			return fmt.Errorf("resolving source file: %w", err)
+		}
+		content, err := os.ReadFile(sourcePath)
 		if err != nil {
 			return fmt.Errorf("reading source file: %w", err)
 		}
-		if err := createTemplatedFile(fullPath, string(content), file.Values); err != nil {
-			return copyFile(file.SourceFile, fullPath)
+		if err := createTemplatedFile(fullPath, string(content), file.Values, partialsDir, file.Partials, sources); err != nil {
+			return copyFile(sourcePath, fullPath)
 		}
 	}
 	return nil
@@ -381,7 +525,12 @@ func downloadFileContent(fileURL string) (string, error) {
 }
 
 // createTemplatedFile creates a file from a template content and values.
-func createTemplatedFile(path, content string, values map[string]interface{}) error {
+func createTemplatedFile(path, content string, values map[string]interface{}, partialsDir string, partials []string, sources map[string]string) error {
+	rendered, err := renderTemplatedContent(filepath.Base(path), content, values, partialsDir, partials, sources)
+	if err != nil {
+		return err
+	}
+
 	f, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("creating file: %w", err)
@@ -392,16 +541,29 @@ func createTemplatedFile(path, content string, values map[string]interface{}) er
 		}
 	}()
 
-	tmpl, err := template.New(filepath.Base(path)).Parse(content)
+	if _, err := f.WriteString(rendered); err != nil {
+		return fmt.Errorf("writing templated file: %w", err)
+	}
+
+	return nil
+}
+
+// renderTemplateString executes a text/template named name against content
+// and values, returning the rendered result as a string. The hooks use it for
+// commit messages and PR text; it has neither the shared function library nor
+// partials, which file rendering gets from renderTemplatedContent.
+func renderTemplateString(name, content string, values map[string]interface{}) (string, error) {
+	tmpl, err := template.New(name).Parse(content)
 	if err != nil {
-		return fmt.Errorf("parsing template: %w", err)
+		return "", fmt.Errorf("parsing template: %w", err)
 	}
 
-	if err := tmpl.Execute(f, values); err != nil {
-		return err // Return the error to indicate templating failure
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return "", err // Return the error to indicate templating failure
 	}
 
-	return nil
+	return buf.String(), nil
 }
 
 // copyFile copies a file from source to destination.
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reporter receives structured progress events as repositories and files
+// are processed, so a run over hundreds of repositories stays observable.
+type Reporter interface {
+	RepoStart(repo string)
+	FileWritten(repo, file string)
+	FileSkipped(repo, file, reason string)
+	RepoDone(repo string, err error)
+	Error(repo, file string, err error)
+	Close()
+}
+
+// newReporter picks the Reporter implementation for this run: silent, JSON
+// lines (for log pipelines), a TTY progress bar, or a plain log-line
+// fallback when stderr isn't a terminal.
+func newReporter(totalRepos int, silent, noProgress bool, logFormat string, out *os.File) Reporter {
+	switch {
+	case silent:
+		return noopReporter{}
+	case logFormat == "json":
+		return &jsonReporter{enc: json.NewEncoder(out)}
+	case !noProgress && isTerminal(out):
+		return newProgressReporter(totalRepos, out)
+	default:
+		return plainReporter{}
+	}
+}
+
+// isTerminal reports whether f looks like an interactive terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// noopReporter discards every event, used with --silent.
+type noopReporter struct{}
+
+func (noopReporter) RepoStart(string)                   {}
+func (noopReporter) FileWritten(string, string)         {}
+func (noopReporter) FileSkipped(string, string, string) {}
+func (noopReporter) RepoDone(string, error)             {}
+func (noopReporter) Error(string, string, error)        {}
+func (noopReporter) Close()                             {}
+
+// plainReporter emits one log line per event, used when stderr isn't a
+// terminal and --log-format=json wasn't requested.
+type plainReporter struct{}
+
+func (plainReporter) RepoStart(repo string) {
+	log.Printf("Processing repository: %s\n", repo)
+}
+
+func (plainReporter) FileWritten(repo, file string) {
+	log.Printf("Wrote %s/%s\n", repo, file)
+}
+
+func (plainReporter) FileSkipped(repo, file, reason string) {
+	log.Printf("Skipped %s/%s: %s\n", repo, file, reason)
+}
+
+func (plainReporter) RepoDone(repo string, err error) {
+	if err != nil {
+		log.Printf("Repository '%s' failed: %v\n", repo, err)
+		return
+	}
+	log.Printf("Repository '%s' processed successfully.\n", repo)
+}
+
+func (plainReporter) Error(repo, file string, err error) {
+	log.Printf("Error in %s/%s: %v\n", repo, file, err)
+}
+
+func (plainReporter) Close() {}
+
+// jsonEvent is one line of --log-format=json output.
+type jsonEvent struct {
+	Event  string `json:"event"`
+	Time   string `json:"time"`
+	Repo   string `json:"repo,omitempty"`
+	File   string `json:"file,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// jsonReporter emits one JSON object per event, suitable for ingestion by
+// log pipelines.
+type jsonReporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (r *jsonReporter) emit(ev jsonEvent) {
+	ev.Time = time.Now().Format(time.RFC3339Nano)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = r.enc.Encode(ev)
+}
+
+func (r *jsonReporter) RepoStart(repo string) {
+	r.emit(jsonEvent{Event: "repo_start", Repo: repo})
+}
+
+func (r *jsonReporter) FileWritten(repo, file string) {
+	r.emit(jsonEvent{Event: "file_written", Repo: repo, File: file})
+}
+
+func (r *jsonReporter) FileSkipped(repo, file, reason string) {
+	r.emit(jsonEvent{Event: "file_skipped", Repo: repo, File: file, Reason: reason})
+}
+
+func (r *jsonReporter) RepoDone(repo string, err error) {
+	ev := jsonEvent{Event: "repo_done", Repo: repo}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	r.emit(ev)
+}
+
+func (r *jsonReporter) Error(repo, file string, err error) {
+	r.emit(jsonEvent{Event: "error", Repo: repo, File: file, Error: err.Error()})
+}
+
+func (r *jsonReporter) Close() {}
+
+// progressReporter draws a single-line, cheggaaa/pb-style progress bar to a
+// terminal: repos completed out of the total, current repo/file, files/sec
+// throughput, and an ETA extrapolated from the average repo duration so far.
+type progressReporter struct {
+	mu         sync.Mutex
+	out        *os.File
+	startTime  time.Time
+	reposTotal int
+	reposDone  int
+	filesDone  int
+	current    string
+}
+
+func newProgressReporter(totalRepos int, out *os.File) *progressReporter {
+	return &progressReporter{out: out, startTime: time.Now(), reposTotal: totalRepos}
+}
+
+func (p *progressReporter) RepoStart(repo string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = repo
+	p.render()
+}
+
+func (p *progressReporter) FileWritten(repo, file string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.filesDone++
+	p.current = fmt.Sprintf("%s: %s", repo, file)
+	p.render()
+}
+
+func (p *progressReporter) FileSkipped(repo, file, reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = fmt.Sprintf("%s: %s (skipped: %s)", repo, file, reason)
+	p.render()
+}
+
+func (p *progressReporter) RepoDone(repo string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reposDone++
+	p.render()
+}
+
+func (p *progressReporter) Error(repo, file string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.out, "\n[error] repo=%s file=%s: %v\n", repo, file, err)
+	p.render()
+}
+
+func (p *progressReporter) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.out)
+}
+
+// render redraws the progress line in place using a carriage return.
+func (p *progressReporter) render() {
+	elapsed := time.Since(p.startTime).Seconds()
+	throughput := 0.0
+	if elapsed > 0 {
+		throughput = float64(p.filesDone) / elapsed
+	}
+
+	eta := "?"
+	if p.reposDone > 0 && p.reposDone < p.reposTotal {
+		perRepo := elapsed / float64(p.reposDone)
+		remaining := p.reposTotal - p.reposDone
+		eta = fmt.Sprintf("%.0fs", perRepo*float64(remaining))
+	}
+
+	fmt.Fprintf(p.out, "\r[%d/%d repos] %.1f files/s eta=%s %s\x1b[K", p.reposDone, p.reposTotal, throughput, eta, p.current)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewReporterSelection(t *testing.T) {
+	tests := []struct {
+		name       string
+		silent     bool
+		noProgress bool
+		logFormat  string
+		want       string
+	}{
+		{name: "silent wins over everything", silent: true, logFormat: "json", want: "noopReporter"},
+		{name: "json log format", logFormat: "json", want: "*jsonReporter"},
+		{name: "plain fallback when no-progress", noProgress: true, want: "plainReporter"},
+	}
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("opening %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newReporter(1, tt.silent, tt.noProgress, tt.logFormat, devNull)
+			if name := reporterTypeName(got); name != tt.want {
+				t.Errorf("newReporter(...) type = %s, want %s", name, tt.want)
+			}
+		})
+	}
+}
+
+// reporterTypeName returns a short name for r's concrete type, used to assert
+// which Reporter implementation newReporter picked.
+func reporterTypeName(r Reporter) string {
+	switch r.(type) {
+	case noopReporter:
+		return "noopReporter"
+	case *jsonReporter:
+		return "*jsonReporter"
+	case plainReporter:
+		return "plainReporter"
+	case *progressReporter:
+		return "*progressReporter"
+	default:
+		return "unknown"
+	}
+}
+
+// renderProgressLine points p at a temporary file, calls p.render() once and
+// returns what it wrote.
+func renderProgressLine(t *testing.T, p *progressReporter) string {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "progress")
+	if err != nil {
+		t.Fatalf("creating temp file: %v", err)
+	}
+	defer f.Close()
+
+	p.out = f
+	p.render()
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("reading rendered progress line: %v", err)
+	}
+	return string(data)
+}
+
+// TestProgressReporterThroughput exercises the files/sec math in render() by
+// backdating startTime instead of sleeping in the test.
+func TestProgressReporterThroughput(t *testing.T) {
+	p := &progressReporter{reposTotal: 4, startTime: time.Now().Add(-10 * time.Second), filesDone: 20}
+
+	line := renderProgressLine(t, p)
+
+	if !strings.Contains(line, " 2.0 files/s ") {
+		t.Errorf("progress line = %q, want approximately 2.0 files/s", line)
+	}
+}
+
+// TestProgressReporterETA exercises render()'s "extrapolate from average repo
+// duration so far" ETA math for the in-progress and not-yet-started cases.
+func TestProgressReporterETA(t *testing.T) {
+	p := &progressReporter{reposTotal: 4, startTime: time.Now().Add(-10 * time.Second), reposDone: 2}
+
+	if line := renderProgressLine(t, p); !strings.Contains(line, "eta=10s ") {
+		t.Errorf("progress line = %q, want eta=10s (2 repos done in ~10s, 2 remaining)", line)
+	}
+
+	// No repos done yet: render() falls back to "?" rather than dividing by
+	// zero.
+	p.reposDone = 0
+	if line := renderProgressLine(t, p); !strings.Contains(line, "eta=? ") {
+		t.Errorf("progress line = %q, want eta=? when reposDone is 0", line)
+	}
+}
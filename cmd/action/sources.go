@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceConfig describes a remote (or local) location that template groups
+// can be fetched from. It is declared in the top-level `sources:` block of
+// the configuration file.
+type SourceConfig struct {
+	Name    string `yaml:"name"`
+	Type    string `yaml:"type"` // git|https|local
+	Repo    string `yaml:"repo"`
+	Ref     string `yaml:"ref"`
+	Subpath string `yaml:"subpath"`
+}
+
+// registryEntry records when a source was last fetched so that unchanged
+// refs can skip a re-fetch on subsequent runs.
+type registryEntry struct {
+	Repo       string    `yaml:"repo"`
+	Ref        string    `yaml:"ref"`
+	LastUpdate time.Time `yaml:"lastUpdate"`
+}
+
+// registry is the on-disk `registry.yaml` sidecar tracking fetched sources.
+type registry struct {
+	Entries map[string]registryEntry `yaml:"entries"`
+}
+
+// cacheDir returns the local cache directory for a given source, e.g.
+// ~/.cache/structuresmith/<name>@<ref>.
+func cacheDir(source SourceConfig) (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving user cache dir: %w", err)
+	}
+	ref := source.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return filepath.Join(base, "structuresmith", fmt.Sprintf("%s@%s", source.Name, ref)), nil
+}
+
+// loadRegistry reads registry.yaml from the cache root, returning an empty
+// registry if it doesn't exist yet.
+func loadRegistry(path string) (registry, error) {
+	reg := registry{Entries: make(map[string]registryEntry)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return reg, nil
+	}
+	if err != nil {
+		return reg, fmt.Errorf("reading registry: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &reg); err != nil {
+		return reg, fmt.Errorf("unmarshalling registry: %w", err)
+	}
+	if reg.Entries == nil {
+		reg.Entries = make(map[string]registryEntry)
+	}
+	return reg, nil
+}
+
+// saveRegistry writes the registry back to disk.
+func saveRegistry(path string, reg registry) error {
+	data, err := yaml.Marshal(reg)
+	if err != nil {
+		return fmt.Errorf("marshalling registry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("creating registry dir: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// resolveSources fetches (or reuses the cached copy of) every configured
+// source and returns a map of source name to its local directory on disk.
+// In offline mode, sources must already be present in the cache.
+func resolveSources(sources []SourceConfig, offline bool) (map[string]string, error) {
+	registryPath, err := registryPath()
+	if err != nil {
+		return nil, err
+	}
+	reg, err := loadRegistry(registryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]string, len(sources))
+	for _, source := range sources {
+		dir, err := cacheDir(source)
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", source.Name, err)
+		}
+
+		entry, known := reg.Entries[source.Name]
+		upToDate := known && entry.Repo == source.Repo && entry.Ref == source.Ref && dirExists(dir)
+
+		switch {
+		case source.Type == "local":
+			dir = source.Repo
+			if !dirExists(dir) {
+				return nil, fmt.Errorf("local source %s not found at %s", source.Name, dir)
+			}
+		case offline:
+			if !dirExists(dir) {
+				return nil, fmt.Errorf("source %s not cached and --offline was set", source.Name)
+			}
+		case upToDate:
+			log.Printf("Source %s@%s is up to date, skipping fetch.\n", source.Name, source.Ref)
+		default:
+			if err := fetchSource(source, dir); err != nil {
+				return nil, fmt.Errorf("fetching source %s: %w", source.Name, err)
+			}
+			reg.Entries[source.Name] = registryEntry{Repo: source.Repo, Ref: source.Ref, LastUpdate: time.Now()}
+		}
+
+		resolved[source.Name] = dir
+	}
+
+	if !offline {
+		if err := saveRegistry(registryPath, reg); err != nil {
+			return nil, fmt.Errorf("saving registry: %w", err)
+		}
+	}
+	return resolved, nil
+}
+
+// registryPath returns the path of the shared registry.yaml sidecar.
+func registryPath() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving user cache dir: %w", err)
+	}
+	return filepath.Join(base, "structuresmith", "registry.yaml"), nil
+}
+
+// fetchSource clones or downloads a source into dir, replacing any existing
+// contents.
+func fetchSource(source SourceConfig, dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("clearing cache dir: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), os.ModePerm); err != nil {
+		return fmt.Errorf("creating cache dir: %w", err)
+	}
+
+	switch source.Type {
+	case "git":
+		return fetchGitSource(source, dir)
+	case "https":
+		return fetchHTTPSource(source, dir)
+	default:
+		return fmt.Errorf("unsupported source type: %s", source.Type)
+	}
+}
+
+// fetchGitSource clones a git repository, optionally pinned to a ref or
+// commit SHA, via the system `git` binary.
+func fetchGitSource(source SourceConfig, dir string) error {
+	args := []string{"clone"}
+	if source.Ref != "" && !looksLikeSHA(source.Ref) {
+		args = append(args, "--branch", source.Ref, "--depth", "1")
+	}
+	args = append(args, source.Repo, dir)
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+
+	if source.Ref != "" && looksLikeSHA(source.Ref) {
+		checkout := exec.Command("git", "-C", dir, "checkout", source.Ref)
+		checkout.Stdout = os.Stdout
+		checkout.Stderr = os.Stderr
+		if err := checkout.Run(); err != nil {
+			return fmt.Errorf("git checkout %s failed: %w", source.Ref, err)
+		}
+	}
+	return nil
+}
+
+// fetchHTTPSource downloads source.Repo and writes it into dir as a single
+// file named after the URL's base name. Archives are not extracted.
+func fetchHTTPSource(source SourceConfig, dir string) error {
+	content, err := downloadFileContent(source.Repo)
+	if err != nil {
+		return fmt.Errorf("downloading source archive: %w", err)
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating source dir: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, filepath.Base(source.Repo)), []byte(content), 0o644)
+}
+
+// looksLikeSHA reports whether ref looks like a pinned commit SHA rather
+// than a branch or tag name.
+func looksLikeSHA(ref string) bool {
+	if len(ref) < 7 || len(ref) > 40 {
+		return false
+	}
+	for _, r := range ref {
+		if !strings.Contains("0123456789abcdef", strings.ToLower(string(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+// dirExists reports whether path exists and is a directory.
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// resolveSourceFile resolves a `sourceName:path/inside/repo` reference
+// against the resolved sources map, returning its path on disk (relative
+// when a local source's repo path is relative). If
+// ref does not contain a source prefix, it is returned unchanged so local
+// `templatesDir`-relative files keep working as before.
+func resolveSourceFile(ref string, sources map[string]string) (string, error) {
+	name, rel, found := strings.Cut(ref, ":")
+	if !found {
+		return ref, nil
+	}
+	dir, ok := sources[name]
+	if !ok {
+		return "", fmt.Errorf("unknown source %q referenced by sourceFile %q", name, ref)
+	}
+	return filepath.Join(dir, rel), nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLooksLikeSHA(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  string
+		want bool
+	}{
+		{name: "full sha", ref: "0123456789abcdef0123456789abcdef01234567", want: true},
+		{name: "short sha", ref: "abc1234", want: true},
+		{name: "uppercase sha", ref: "ABC1234", want: true},
+		{name: "too short", ref: "abc12", want: false},
+		{name: "branch name", ref: "main", want: false},
+		{name: "tag with dots", ref: "v1.2.3", want: false},
+		{name: "empty", ref: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksLikeSHA(tt.ref); got != tt.want {
+				t.Errorf("looksLikeSHA(%q) = %v, want %v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSourceFile(t *testing.T) {
+	sources := map[string]string{
+		"platform": "/cache/platform@main",
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{name: "no source prefix", ref: "templates/ci.yml", want: "templates/ci.yml"},
+		{name: "known source", ref: "platform:files/ci.yml", want: filepath.Join("/cache/platform@main", "files/ci.yml")},
+		{name: "unknown source", ref: "missing:files/ci.yml", wantErr: true},
+		{name: "windows style path without colon", ref: `templates\ci.yml`, want: `templates\ci.yml`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSourceFile(tt.ref, sources)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolveSourceFile(%q) = nil error, want an error", tt.ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveSourceFile(%q) returned error: %v", tt.ref, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveSourceFile(%q) = %q, want %q", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheDirVariesByRef(t *testing.T) {
+	withRef, err := cacheDir(SourceConfig{Name: "platform", Ref: "v1"})
+	if err != nil {
+		t.Fatalf("cacheDir returned error: %v", err)
+	}
+	withoutRef, err := cacheDir(SourceConfig{Name: "platform"})
+	if err != nil {
+		t.Fatalf("cacheDir returned error: %v", err)
+	}
+
+	if withRef == withoutRef {
+		t.Errorf("cacheDir should differ between a pinned ref and no ref, got %q for both", withRef)
+	}
+	if filepath.Base(withRef) != "platform@v1" {
+		t.Errorf("cacheDir(ref=v1) base = %q, want %q", filepath.Base(withRef), "platform@v1")
+	}
+	if filepath.Base(withoutRef) != "platform@HEAD" {
+		t.Errorf("cacheDir(no ref) base = %q, want %q", filepath.Base(withoutRef), "platform@HEAD")
+	}
+}
+
+func TestDirExists(t *testing.T) {
+	dir := t.TempDir()
+	if !dirExists(dir) {
+		t.Errorf("dirExists(%q) = false, want true", dir)
+	}
+
+	file := filepath.Join(dir, "file.txt")
+	writeTestFiles(t, dir, "file.txt")
+	if dirExists(file) {
+		t.Errorf("dirExists(%q) = true, want false for a regular file", file)
+	}
+
+	if dirExists(filepath.Join(dir, "does-not-exist")) {
+		t.Errorf("dirExists should be false for a path that doesn't exist")
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateFuncs returns the shared, Sprig-like function set available to
+// every template structuresmith renders: defaulting, YAML marshalling,
+// indentation helpers, and a handful of string/date utilities.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"default":  defaultFunc,
+		"toYaml":   toYamlFunc,
+		"indent":   indentFunc,
+		"nindent":  nindentFunc,
+		"quote":    quoteFunc,
+		"hasKey":   hasKeyFunc,
+		"env":      os.Getenv,
+		"now":      time.Now,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"trim":     strings.TrimSpace,
+		"join":     joinFunc,
+		"split":    splitFunc,
+		"contains": strings.Contains,
+		"replace":  replaceFunc,
+	}
+}
+
+// defaultFunc returns def when val is nil or an empty string, mirroring
+// Sprig's `default`.
+func defaultFunc(def, val interface{}) interface{} {
+	if val == nil {
+		return def
+	}
+	if s, ok := val.(string); ok && s == "" {
+		return def
+	}
+	return val
+}
+
+// toYamlFunc marshals v to a YAML document, trimming the trailing newline
+// so it composes cleanly with indent/nindent.
+func toYamlFunc(v interface{}) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshalling to YAML: %w", err)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// indentFunc prepends spaces worth of padding to every line of v.
+func indentFunc(spaces int, v string) string {
+	pad := strings.Repeat(" ", spaces)
+	lines := strings.Split(v, "\n")
+	for i, line := range lines {
+		lines[i] = pad + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nindentFunc is indentFunc with a leading newline, for inserting an
+// indented block after a YAML key.
+func nindentFunc(spaces int, v string) string {
+	return "\n" + indentFunc(spaces, v)
+}
+
+// quoteFunc renders v as a double-quoted Go string literal.
+func quoteFunc(v interface{}) string {
+	return fmt.Sprintf("%q", fmt.Sprintf("%v", v))
+}
+
+// hasKeyFunc reports whether m contains key.
+func hasKeyFunc(m map[string]interface{}, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// joinFunc joins items with sep.
+func joinFunc(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// splitFunc splits s on sep.
+func splitFunc(sep, s string) []string {
+	return strings.Split(s, sep)
+}
+
+// replaceFunc replaces every occurrence of old with new in s.
+func replaceFunc(old, new, s string) string {
+	return strings.ReplaceAll(s, old, new)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// buildTemplateEnv constructs a shared *template.Template set carrying the
+// Sprig-like function library plus every *.tmpl file in partialsDir (when it
+// exists) and any group-specific partials listed in extraPartials, so a
+// rendered file's `{{ template "name" . }}` can resolve partials registered
+// by either.
+func buildTemplateEnv(partialsDir string, extraPartials []string, sources map[string]string) (*template.Template, error) {
+	root := template.New("root").Funcs(templateFuncs())
+
+	if partialsDir != "" {
+		matches, err := filepath.Glob(filepath.Join(partialsDir, "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("globbing partials dir: %w", err)
+		}
+		for _, match := range matches {
+			if err := parsePartialFile(root, match); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, partial := range extraPartials {
+		partialPath, err := resolveSourceFile(partial, sources)
+		if err != nil {
+			return nil, fmt.Errorf("resolving partial %q: %w", partial, err)
+		}
+		if err := parsePartialFile(root, partialPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
+
+// parsePartialFile reads path and parses it into root, registering whatever
+// named templates it `{{ define }}`s.
+func parsePartialFile(root *template.Template, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading partial %s: %w", path, err)
+	}
+	if _, err := root.Parse(string(content)); err != nil {
+		return fmt.Errorf("parsing partial %s: %w", path, err)
+	}
+	return nil
+}
+
+// renderTemplatedContent parses content under name into a template
+// environment that also has the shared function library and every partial
+// (global and group-specific) available, then executes it against values.
+func renderTemplatedContent(name, content string, values map[string]interface{}, partialsDir string, partials []string, sources map[string]string) (string, error) {
+	root, err := buildTemplateEnv(partialsDir, partials, sources)
+	if err != nil {
+		return "", fmt.Errorf("building template environment: %w", err)
+	}
+
+	tmpl, err := root.New(name).Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("parsing template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, values); err != nil {
+		return "", err // Return the error to indicate templating failure
+	}
+
+	return buf.String(), nil
+}